@@ -0,0 +1,69 @@
+package main
+
+import (
+	"math"
+	"sort"
+)
+
+// DiversifyMMR reranks recs using Maximal Marginal Relevance: starting
+// from an empty selected set, it repeatedly picks the candidate c
+// maximizing lambda*rel(c) - (1-lambda)*max_{s in selected} sim(c,s),
+// where rel(c) is c's existing score and sim reuses CosineSimilarity over
+// ds.Features. This keeps a dense industry cluster of near-identical
+// startups from crowding out everything else in the final list.
+func (ds *DataStore) DiversifyMMR(recs []Recommendation, lambda float64, n int) []Recommendation {
+	candidates := append([]Recommendation(nil), recs...)
+	selected := make([]Recommendation, 0, n)
+
+	for len(selected) < n && len(candidates) > 0 {
+		bestIdx := -1
+		bestScore := math.Inf(-1)
+		for i, c := range candidates {
+			maxSim := 0.0
+			for _, s := range selected {
+				if sim := CosineSimilarity(ds.Features[c.TargetID], ds.Features[s.TargetID]); sim > maxSim {
+					maxSim = sim
+				}
+			}
+			mmrScore := lambda*c.Score - (1-lambda)*maxSim
+			if mmrScore > bestScore {
+				bestScore = mmrScore
+				bestIdx = i
+			}
+		}
+		selected = append(selected, candidates[bestIdx])
+		candidates = append(candidates[:bestIdx], candidates[bestIdx+1:]...)
+	}
+	return selected
+}
+
+// NoveltyBoost down-weights globally popular items by -beta*log(popularity)
+// so a recommendation list isn't just "what's already popular," returning
+// a new slice re-sorted by the adjusted score.
+func (ds *DataStore) NoveltyBoost(recs []Recommendation, beta float64) []Recommendation {
+	popularity := ds.popularityCounts()
+
+	out := make([]Recommendation, len(recs))
+	for i, r := range recs {
+		adjusted := r.Score
+		if pop := popularity[r.TargetID]; pop > 0 {
+			adjusted -= beta * math.Log(pop)
+		}
+		out[i] = Recommendation{TargetID: r.TargetID, Type: r.Type, Score: adjusted}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Score > out[j].Score })
+	return out
+}
+
+// popularityCounts sums raw interaction scores per target across every
+// user, the same aggregate GetPopularItems ranks by before trust
+// weighting is applied.
+func (ds *DataStore) popularityCounts() map[string]float64 {
+	counts := make(map[string]float64)
+	for _, userInteractions := range ds.allInteractions() {
+		for targetID, score := range userInteractions {
+			counts[targetID] += score
+		}
+	}
+	return counts
+}
@@ -0,0 +1,213 @@
+package main
+
+import (
+	"math"
+	"sort"
+)
+
+// itemSimilarityCache holds the cosine item-item similarity matrix used by
+// ItemBasedCollaborativeFiltering. Rows are recomputed lazily: adding an
+// interaction only marks the affected item's row dirty instead of
+// recomputing the whole matrix.
+//
+// Access to a DataStore's itemSim is guarded by itemSimMu, since
+// ProcessRecommendations reaches ItemBasedCollaborativeFiltering/
+// SimilarItems (and AddInteraction may be called) from multiple
+// goroutines sharing the same DataStore.
+type itemSimilarityCache struct {
+	sim   map[string]map[string]float64 // itemID -> otherItemID -> similarity
+	dirty map[string]bool               // items whose row needs recomputing
+}
+
+// itemUserVectors transposes the interaction matrix into itemID -> userID
+// -> weighted score, the shape ItemBasedCollaborativeFiltering's cosine
+// similarity is computed over.
+func (ds *DataStore) itemUserVectors() map[string]map[string]float64 {
+	vectors := make(map[string]map[string]float64)
+	for userID := range ds.allInteractions() {
+		for itemID, score := range ds.weightedVector(userID) {
+			if vectors[itemID] == nil {
+				vectors[itemID] = make(map[string]float64)
+			}
+			vectors[itemID][userID] = score
+		}
+	}
+	return vectors
+}
+
+// ensureItemSimilarity rebuilds/repairs the cached item-item similarity
+// matrix under itemSimMu (building it on first use and recomputing only
+// rows marked dirty since the last call), then returns a snapshot copy
+// safe for the caller to read without holding the lock.
+func (ds *DataStore) ensureItemSimilarity() map[string]map[string]float64 {
+	ds.itemSimMu.Lock()
+	defer ds.itemSimMu.Unlock()
+
+	if ds.itemSim == nil {
+		ds.itemSim = &itemSimilarityCache{
+			sim:   make(map[string]map[string]float64),
+			dirty: make(map[string]bool),
+		}
+	}
+	cache := ds.itemSim
+	if len(cache.sim) == 0 || len(cache.dirty) > 0 {
+		vectors := ds.itemUserVectors()
+		targets := cache.dirty
+		if len(cache.sim) == 0 {
+			// first build: every item's row is "dirty"
+			targets = make(map[string]bool, len(vectors))
+			for itemID := range vectors {
+				targets[itemID] = true
+			}
+		}
+		for itemID := range targets {
+			ds.recomputeItemRow(itemID, vectors)
+		}
+		cache.dirty = make(map[string]bool)
+	}
+
+	return cloneSimMatrix(cache.sim)
+}
+
+// cloneSimMatrix deep-copies a similarity matrix so callers can read it
+// without racing a later recompute of the shared cache.
+func cloneSimMatrix(sim map[string]map[string]float64) map[string]map[string]float64 {
+	out := make(map[string]map[string]float64, len(sim))
+	for id, row := range sim {
+		clone := make(map[string]float64, len(row))
+		for otherID, score := range row {
+			clone[otherID] = score
+		}
+		out[id] = clone
+	}
+	return out
+}
+
+// recomputeItemRow recomputes itemID's similarity against every other
+// item and writes both directions, keeping the matrix symmetric so a
+// single dirty row repairs the whole cache.
+func (ds *DataStore) recomputeItemRow(itemID string, vectors map[string]map[string]float64) {
+	row := make(map[string]float64)
+	for otherID, otherVec := range vectors {
+		if otherID == itemID {
+			continue
+		}
+		row[otherID] = CosineSimilarity(vectors[itemID], otherVec)
+	}
+	ds.itemSim.sim[itemID] = row
+	for otherID, score := range row {
+		if ds.itemSim.sim[otherID] == nil {
+			ds.itemSim.sim[otherID] = make(map[string]float64)
+		}
+		ds.itemSim.sim[otherID][itemID] = score
+	}
+}
+
+// AddInteraction records a new interaction and invalidates only the
+// item-item similarity row it touches, rather than forcing a full
+// recompute on the next ItemBasedCollaborativeFiltering call. It is safe
+// to call concurrently with reads (HybridRecommendation,
+// ItemBasedCollaborativeFiltering, SimilarItems, ...): interactionsMu
+// guards the Interactions/FeedbackTypes mutation below, and itemSimMu
+// guards the similarity cache's dirty flag.
+func (ds *DataStore) AddInteraction(userID, targetID string, score float64, feedback FeedbackType) {
+	ds.interactionsMu.Lock()
+	if ds.Interactions[userID] == nil {
+		ds.Interactions[userID] = make(map[string]float64)
+	}
+	ds.Interactions[userID][targetID] = score
+
+	if ds.FeedbackTypes[userID] == nil {
+		ds.FeedbackTypes[userID] = make(map[string]FeedbackType)
+	}
+	ds.FeedbackTypes[userID][targetID] = feedback
+	ds.interactionsMu.Unlock()
+
+	ds.itemSimMu.Lock()
+	if ds.itemSim != nil {
+		ds.itemSim.dirty[targetID] = true
+	}
+	ds.itemSimMu.Unlock()
+}
+
+// ItemBasedCollaborativeFiltering recommends items similar to the ones
+// userID already interacted with, weighted by the user's own scores:
+// score(i) = sum(sim(i,j)*r_uj) / sum(|sim(i,j)|) over j in rated(u). This
+// tends to be more stable than user-based CF on sparse founder/investor
+// graphs because the similarity matrix changes slowly compared to any one
+// user's interaction history.
+func (ds *DataStore) ItemBasedCollaborativeFiltering(userID string, n int) []Recommendation {
+	sim := ds.ensureItemSimilarity()
+	userVec := ds.weightedVector(userID)
+
+	numerators := make(map[string]float64)
+	denominators := make(map[string]float64)
+	for ratedID, ratedScore := range userVec {
+		for otherID, simScore := range sim[ratedID] {
+			if _, exists := userVec[otherID]; exists {
+				continue
+			}
+			numerators[otherID] += simScore * ratedScore
+			denominators[otherID] += math.Abs(simScore)
+		}
+	}
+
+	var recs []Recommendation
+	for itemID, num := range numerators {
+		denom := denominators[itemID]
+		if denom == 0 {
+			continue
+		}
+		itemType := "startup"
+		if _, exists := ds.Users[itemID]; exists {
+			itemType = "user"
+		}
+		recs = append(recs, Recommendation{TargetID: itemID, Type: itemType, Score: num / denom})
+	}
+	sort.Slice(recs, func(i, j int) bool { return recs[i].Score > recs[j].Score })
+	if len(recs) > n {
+		recs = recs[:n]
+	}
+	return recs
+}
+
+// SimilarItems returns the top-n items most similar to itemID according
+// to the cached item-item similarity matrix ("users who liked X also
+// liked Y").
+func (ds *DataStore) SimilarItems(itemID string, n int) []Recommendation {
+	sim := ds.ensureItemSimilarity()
+
+	var recs []Recommendation
+	for otherID, score := range sim[itemID] {
+		itemType := "startup"
+		if _, exists := ds.Users[otherID]; exists {
+			itemType = "user"
+		}
+		recs = append(recs, Recommendation{TargetID: otherID, Type: itemType, Score: score})
+	}
+	sort.Slice(recs, func(i, j int) bool { return recs[i].Score > recs[j].Score })
+	if len(recs) > n {
+		recs = recs[:n]
+	}
+	return recs
+}
+
+// SimilarUsers returns the top-n users most similar to userID by cosine
+// similarity over their (weighted) interaction vectors.
+func (ds *DataStore) SimilarUsers(userID string, n int) []Recommendation {
+	userVec := ds.weightedVector(userID)
+
+	var recs []Recommendation
+	for otherID := range ds.Users {
+		if otherID == userID {
+			continue
+		}
+		score := CosineSimilarity(userVec, ds.weightedVector(otherID))
+		recs = append(recs, Recommendation{TargetID: otherID, Type: "user", Score: score})
+	}
+	sort.Slice(recs, func(i, j int) bool { return recs[i].Score > recs[j].Score })
+	if len(recs) > n {
+		recs = recs[:n]
+	}
+	return recs
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"math"
 	"sort"
+	"sync"
 )
 
 // User represents a founder or investor
@@ -12,6 +13,12 @@ type User struct {
 	Role     string   // "founder" or "investor"
 	Industry []string // e.g., ["fintech", "AI"]
 	Stage    string   // e.g., "seed", "series-a"
+
+	// Trust is signal quality in [0,1]; 0 is a valid, meaningful value
+	// (zero credibility), so TrustSet distinguishes "never set" from
+	// "explicitly zero" — see DataStore.trustOf.
+	Trust    float64
+	TrustSet bool
 }
 
 // Startup represents a startup
@@ -22,12 +29,23 @@ type Startup struct {
 	Stage     string
 }
 
+// FeedbackType distinguishes interactions that carry an unambiguous
+// strength signal (explicit, e.g. an investment amount or star rating)
+// from ones that only indicate interest (implicit, e.g. a view or follow).
+type FeedbackType string
+
+const (
+	ExplicitFeedback FeedbackType = "explicit"
+	ImplicitFeedback FeedbackType = "implicit"
+)
+
 // Interaction represents user actions (e.g., investment, like)
 type Interaction struct {
 	UserID   string
 	TargetID string // Startup or User ID
 	Type     string // e.g., "invested", "liked"
 	Score    float64
+	Feedback FeedbackType // how Score should be interpreted
 }
 
 // Recommendation holds a recommended item
@@ -39,19 +57,50 @@ type Recommendation struct {
 
 // DataStore holds in-memory data (replace with database in production)
 type DataStore struct {
-	Users        map[string]User
-	Startups     map[string]Startup
-	Interactions map[string]map[string]float64 // UserID -> TargetID -> Score
-	Features     map[string]map[string]float64 // ItemID -> Feature -> Weight
+	Users    map[string]User
+	Startups map[string]Startup
+	Features map[string]map[string]float64 // ItemID -> Feature -> Weight
+
+	// interactionsMu guards Interactions/FeedbackTypes: AddInteraction
+	// writes to them while ProcessRecommendations's goroutines
+	// (HybridRecommendation, ItemBasedCollaborativeFiltering, MFModel
+	// training/recs, ...) read them concurrently from multiple goroutines
+	// sharing this DataStore.
+	interactionsMu sync.RWMutex
+	Interactions   map[string]map[string]float64      // UserID -> TargetID -> Score
+	FeedbackTypes  map[string]map[string]FeedbackType // UserID -> TargetID -> FeedbackType
+
+	// ImplicitAlpha scales raw implicit values (view/like/follow counts)
+	// into confidence weights c = 1 + ImplicitAlpha*value.
+	ImplicitAlpha float64
+
+	mfModel *MFModel // most recently fitted matrix-factorization model, if any
+
+	// contentStore and contentDims cache the ANN index over ds.Features so
+	// ContentBasedFiltering can answer via one VectorStore query instead
+	// of a linear scan; built lazily on first use and guarded by
+	// contentOnce since ProcessRecommendations fans HybridRecommendation
+	// out across goroutines sharing this DataStore.
+	contentOnce  sync.Once
+	contentStore *VectorStore
+	contentDims  map[string]int
+
+	// itemSimMu guards itemSim: ProcessRecommendations reaches
+	// ItemBasedCollaborativeFiltering/SimilarItems (and AddInteraction may
+	// run concurrently) from multiple goroutines sharing this DataStore.
+	itemSimMu sync.Mutex
+	itemSim   *itemSimilarityCache // cached item-item similarity matrix, if built
 }
 
 // NewDataStore initializes the data store
 func NewDataStore() *DataStore {
 	return &DataStore{
-		Users:        make(map[string]User),
-		Startups:     make(map[string]Startup),
-		Interactions: make(map[string]map[string]float64),
-		Features:     make(map[string]map[string]float64),
+		Users:         make(map[string]User),
+		Startups:      make(map[string]Startup),
+		Interactions:  make(map[string]map[string]float64),
+		FeedbackTypes: make(map[string]map[string]FeedbackType),
+		Features:      make(map[string]map[string]float64),
+		ImplicitAlpha: 1.0,
 	}
 }
 
@@ -71,6 +120,12 @@ func (ds *DataStore) LoadSampleData() {
 	ds.Interactions["u2"] = map[string]float64{"s1": 5.0, "u1": 2.0} // Investor funded fintech startup, followed founder
 	ds.Interactions["u3"] = map[string]float64{"s2": 5.0}            // Investor funded AI startup
 
+	// Feedback types: investments are explicit (dollar-denominated signal),
+	// likes/follows are implicit (binary interest signal)
+	ds.FeedbackTypes["u1"] = map[string]FeedbackType{"s2": ImplicitFeedback}
+	ds.FeedbackTypes["u2"] = map[string]FeedbackType{"s1": ExplicitFeedback, "u1": ImplicitFeedback}
+	ds.FeedbackTypes["u3"] = map[string]FeedbackType{"s2": ExplicitFeedback}
+
 	// Features (simplified TF-IDF-like weights)
 	ds.Features["s1"] = map[string]float64{"fintech": 1.0, "seed": 1.0}
 	ds.Features["s2"] = map[string]float64{"AI": 1.0, "series-a": 1.0}
@@ -98,8 +153,89 @@ func CosineSimilarity(vec1, vec2 map[string]float64) float64 {
 	return dotProduct / (math.Sqrt(norm1) * math.Sqrt(norm2))
 }
 
+// feedbackType reports how (userID, targetID)'s Score should be
+// interpreted. Untagged interactions default to explicit, matching the
+// legacy behavior of treating every score as a rating.
+func (ds *DataStore) feedbackType(userID, targetID string) FeedbackType {
+	ds.interactionsMu.RLock()
+	defer ds.interactionsMu.RUnlock()
+	return ds.feedbackTypeLocked(userID, targetID)
+}
+
+// feedbackTypeLocked is feedbackType without taking interactionsMu,
+// for callers that already hold it.
+func (ds *DataStore) feedbackTypeLocked(userID, targetID string) FeedbackType {
+	if types, ok := ds.FeedbackTypes[userID]; ok {
+		if ft, ok := types[targetID]; ok {
+			return ft
+		}
+	}
+	return ExplicitFeedback
+}
+
+// weightedVector returns userID's interaction vector with implicit
+// signals (views, likes, follows) converted into confidence weights
+// c = 1 + ImplicitAlpha*value over a binary preference, while explicit
+// signals (investment amounts, ratings) pass through unchanged so they
+// keep acting as ratings in similarity and MF computations.
+func (ds *DataStore) weightedVector(userID string) map[string]float64 {
+	ds.interactionsMu.RLock()
+	defer ds.interactionsMu.RUnlock()
+
+	raw := ds.Interactions[userID]
+	out := make(map[string]float64, len(raw))
+	for targetID, value := range raw {
+		if ds.feedbackTypeLocked(userID, targetID) == ImplicitFeedback {
+			out[targetID] = 1 + ds.ImplicitAlpha*value
+		} else {
+			out[targetID] = value
+		}
+	}
+	return out
+}
+
+// interactionsSnapshot returns a copy of userID's raw interaction vector,
+// safe for a caller to read after interactionsMu is released.
+func (ds *DataStore) interactionsSnapshot(userID string) map[string]float64 {
+	ds.interactionsMu.RLock()
+	defer ds.interactionsMu.RUnlock()
+	raw := ds.Interactions[userID]
+	out := make(map[string]float64, len(raw))
+	for targetID, value := range raw {
+		out[targetID] = value
+	}
+	return out
+}
+
+// allInteractions returns a deep copy of Interactions, safe for a caller
+// to range over after interactionsMu is released.
+func (ds *DataStore) allInteractions() map[string]map[string]float64 {
+	ds.interactionsMu.RLock()
+	defer ds.interactionsMu.RUnlock()
+	out := make(map[string]map[string]float64, len(ds.Interactions))
+	for userID, targets := range ds.Interactions {
+		clone := make(map[string]float64, len(targets))
+		for targetID, value := range targets {
+			clone[targetID] = value
+		}
+		out[userID] = clone
+	}
+	return out
+}
+
+// hasInteraction reports whether userID has a recorded interaction with
+// targetID, guarded against concurrent AddInteraction calls.
+func (ds *DataStore) hasInteraction(userID, targetID string) bool {
+	ds.interactionsMu.RLock()
+	defer ds.interactionsMu.RUnlock()
+	_, exists := ds.Interactions[userID][targetID]
+	return exists
+}
+
 // UserBasedCollaborativeFiltering recommends based on similar users
 func (ds *DataStore) UserBasedCollaborativeFiltering(userID string, k, n int) []Recommendation {
+	userVec := ds.weightedVector(userID)
+
 	// Compute similarities
 	type sim struct {
 		userID string
@@ -108,7 +244,7 @@ func (ds *DataStore) UserBasedCollaborativeFiltering(userID string, k, n int) []
 	similarities := []sim{}
 	for otherID := range ds.Users {
 		if otherID != userID {
-			simScore := CosineSimilarity(ds.Interactions[userID], ds.Interactions[otherID])
+			simScore := CosineSimilarity(userVec, ds.weightedVector(otherID))
 			similarities = append(similarities, sim{userID: otherID, score: simScore})
 		}
 	}
@@ -118,12 +254,15 @@ func (ds *DataStore) UserBasedCollaborativeFiltering(userID string, k, n int) []
 		similarities = similarities[:k]
 	}
 
-	// Aggregate scores
+	// Aggregate scores, weighting each neighbor's contribution by their
+	// trust (signal quality) so a low-trust neighbor's interactions carry
+	// less weight than a high-trust one's
 	scores := make(map[string]float64)
 	for _, sim := range similarities {
-		for targetID, score := range ds.Interactions[sim.userID] {
-			if _, exists := ds.Interactions[userID][targetID]; !exists {
-				scores[targetID] += sim.score * score
+		trust := ds.trustOf(sim.userID)
+		for targetID, score := range ds.weightedVector(sim.userID) {
+			if !ds.hasInteraction(userID, targetID) {
+				scores[targetID] += sim.score * score * trust
 			}
 		}
 	}
@@ -146,14 +285,21 @@ func (ds *DataStore) UserBasedCollaborativeFiltering(userID string, k, n int) []
 
 // ContentBasedFiltering recommends based on profile similarity
 func (ds *DataStore) ContentBasedFiltering(userID string, n int) []Recommendation {
-	// Build user profile
+	// Build user profile, weighting each source's feature contribution by
+	// its trust when the source is itself a user (e.g. following a
+	// highly-trusted investor should shape the profile more than
+	// following an unknown one)
 	userProfile := make(map[string]float64)
 	totalWeight := 0.0
-	for targetID, score := range ds.Interactions[userID] {
+	for targetID, score := range ds.weightedVector(userID) {
+		weight := score
+		if _, isUser := ds.Users[targetID]; isUser {
+			weight *= ds.trustOf(targetID)
+		}
 		for feature, value := range ds.Features[targetID] {
-			userProfile[feature] += score * value
+			userProfile[feature] += weight * value
 		}
-		totalWeight += score
+		totalWeight += weight
 	}
 	if totalWeight > 0 {
 		for feature := range userProfile {
@@ -161,42 +307,96 @@ func (ds *DataStore) ContentBasedFiltering(userID string, n int) []Recommendatio
 		}
 	}
 
-	// Compute similarities
-	scores := make(map[string]float64)
-	for itemID := range ds.Features {
-		if _, exists := ds.Interactions[userID][itemID]; !exists {
-			scores[itemID] = CosineSimilarity(userProfile, ds.Features[itemID])
+	// Query the ANN index in one shot instead of scanning ds.Features
+	seen := ds.interactionsSnapshot(userID)
+	dims := ds.featureDims()
+	recs := ds.contentVectorStore().FindTopK(denseVector(userProfile, dims), n, func(itemID string) bool {
+		_, exists := seen[itemID]
+		return !exists
+	})
+	for i := range recs {
+		recs[i].Type = "startup"
+		if _, exists := ds.Users[recs[i].TargetID]; exists {
+			recs[i].Type = "user"
 		}
 	}
+	return recs
+}
 
-	// Convert to recommendations
-	var recs []Recommendation
-	for itemID := range scores {
-		itemType := "startup"
-		if _, exists := ds.Users[itemID]; exists {
-			itemType = "user"
+// featureDims assigns each distinct feature name seen across ds.Features
+// a stable dense-vector index, caching the result so repeated calls (and
+// the VectorStore built from it) stay consistent.
+func (ds *DataStore) featureDims() map[string]int {
+	ds.initContentCache()
+	return ds.contentDims
+}
+
+// contentVectorStore lazily builds (and caches) the VectorStore of dense
+// feature embeddings backing ContentBasedFiltering.
+func (ds *DataStore) contentVectorStore() *VectorStore {
+	ds.initContentCache()
+	return ds.contentStore
+}
+
+// initContentCache builds contentDims and contentStore together, exactly
+// once, behind contentOnce: ProcessRecommendations calls HybridRecommendation
+// (and so ContentBasedFiltering) from multiple goroutines sharing this
+// DataStore, so the lazy build itself must be synchronized rather than
+// relying on a nil check.
+func (ds *DataStore) initContentCache() {
+	ds.contentOnce.Do(func() {
+		dims := make(map[string]int)
+		for _, features := range ds.Features {
+			for feature := range features {
+				if _, ok := dims[feature]; !ok {
+					dims[feature] = len(dims)
+				}
+			}
 		}
-		recs = append(recs, Recommendation{TargetID: itemID, Type: itemType, Score: scores[itemID]})
-	}
-	sort.Slice(recs, func(i, j int) bool { return recs[i].Score > recs[j].Score })
-	if len(recs) > n {
-		recs = recs[:n]
-	}
-	return recs
+		ds.contentDims = dims
+
+		store := NewVectorStore()
+		for itemID, features := range ds.Features {
+			store.Upsert(itemID, denseVector(features, dims))
+		}
+		ds.contentStore = store
+	})
 }
 
-// HybridRecommendation combines collaborative and content-based
-func (ds *DataStore) HybridRecommendation(userID string, k, n int, alpha float64) []Recommendation {
+// HybridWeights controls how much each underlying recommender contributes
+// to a blended HybridRecommendation score. Weights need not sum to 1; they
+// are applied directly to each recommender's raw score.
+type HybridWeights struct {
+	Collaborative float64
+	Content       float64
+	MF            float64 // ignored if the DataStore has no fitted MF model
+
+	// DiversifyLambda and NoveltyBeta configure optional reranking stages
+	// applied after scores are combined; 0 disables each stage.
+	DiversifyLambda float64 // MMR relevance/diversity trade-off in [0,1]
+	NoveltyBeta     float64 // strength of the popularity down-weighting
+}
+
+// HybridRecommendation combines collaborative, content-based, and (if
+// trained) matrix-factorization scores. Explicit/implicit feedback
+// handling is inherited from the underlying recommenders, which infer it
+// per interaction via ds.FeedbackTypes.
+func (ds *DataStore) HybridRecommendation(userID string, k, n int, weights HybridWeights) []Recommendation {
 	collabRecs := ds.UserBasedCollaborativeFiltering(userID, k, n*2) // Get more to merge
 	contentRecs := ds.ContentBasedFiltering(userID, n*2)
 
 	// Combine scores
 	scores := make(map[string]float64)
 	for _, rec := range collabRecs {
-		scores[rec.TargetID] += alpha * rec.Score
+		scores[rec.TargetID] += weights.Collaborative * rec.Score
 	}
 	for _, rec := range contentRecs {
-		scores[rec.TargetID] += (1 - alpha) * rec.Score
+		scores[rec.TargetID] += weights.Content * rec.Score
+	}
+	if ds.mfModel != nil && weights.MF != 0 {
+		for _, rec := range ds.MFRecommendations(userID, n*2) {
+			scores[rec.TargetID] += weights.MF * rec.Score
+		}
 	}
 
 	// Fallback to popular items for cold start
@@ -216,6 +416,13 @@ func (ds *DataStore) HybridRecommendation(userID string, k, n int, alpha float64
 		recs = append(recs, Recommendation{TargetID: itemID, Type: itemType, Score: score})
 	}
 	sort.Slice(recs, func(i, j int) bool { return recs[i].Score > recs[j].Score })
+
+	if weights.NoveltyBeta != 0 {
+		recs = ds.NoveltyBoost(recs, weights.NoveltyBeta)
+	}
+	if weights.DiversifyLambda != 0 {
+		return ds.DiversifyMMR(recs, weights.DiversifyLambda, n)
+	}
 	if len(recs) > n {
 		recs = recs[:n]
 	}
@@ -225,9 +432,10 @@ func (ds *DataStore) HybridRecommendation(userID string, k, n int, alpha float64
 // GetPopularItems returns the most interacted items
 func (ds *DataStore) GetPopularItems(n int) []string {
 	scores := make(map[string]float64)
-	for _, userInteractions := range ds.Interactions {
+	for userID, userInteractions := range ds.allInteractions() {
+		trust := ds.trustOf(userID)
 		for targetID, score := range userInteractions {
-			scores[targetID] += score
+			scores[targetID] += score * trust
 		}
 	}
 	var items []string
@@ -242,7 +450,7 @@ func (ds *DataStore) GetPopularItems(n int) []string {
 }
 
 // Concurrent recommendation processing
-func (ds *DataStore) ProcessRecommendations(users []string, k, n int, alpha float64) map[string][]Recommendation {
+func (ds *DataStore) ProcessRecommendations(users []string, k, n int, weights HybridWeights) map[string][]Recommendation {
 	results := make(map[string][]Recommendation)
 	resultChan := make(chan struct {
 		userID string
@@ -252,7 +460,7 @@ func (ds *DataStore) ProcessRecommendations(users []string, k, n int, alpha floa
 	// Spawn goroutines
 	for _, userID := range users {
 		go func(id string) {
-			recs := ds.HybridRecommendation(id, k, n, alpha)
+			recs := ds.HybridRecommendation(id, k, n, weights)
 			resultChan <- struct {
 				userID string
 				recs   []Recommendation
@@ -275,7 +483,8 @@ func main() {
 
 	// Test recommendation for a user
 	userID := "u1" // Fintech founder
-	recs := ds.HybridRecommendation(userID, 2, 3, 0.6)
+	weights := HybridWeights{Collaborative: 0.6, Content: 0.4}
+	recs := ds.HybridRecommendation(userID, 2, 3, weights)
 	fmt.Printf("Recommendations for %s:\n", userID)
 	for _, rec := range recs {
 		fmt.Printf("  %s (%s): %.2f\n", rec.TargetID, rec.Type, rec.Score)
@@ -283,7 +492,7 @@ func main() {
 
 	// Test concurrent recommendations
 	users := []string{"u1", "u2", "u3"}
-	results := ds.ProcessRecommendations(users, 2, 3, 0.6)
+	results := ds.ProcessRecommendations(users, 2, 3, weights)
 	fmt.Println("\nConcurrent Recommendations:")
 	for userID, recs := range results {
 		fmt.Printf("%s:\n", userID)
@@ -0,0 +1,363 @@
+package main
+
+import (
+	"math"
+	"math/rand"
+	"sync"
+)
+
+// mfSeed keeps factor initialization reproducible across fits/tests.
+const mfSeed = 42
+
+// MFModel holds trained latent user/item factors for matrix-factorization
+// recommendations, along with the ID<->index mapping used to look rows up.
+type MFModel struct {
+	ds *DataStore
+
+	userIndex map[string]int
+	itemIndex map[string]int
+	userIDs   []string
+	itemIDs   []string
+
+	P [][]float64 // users x factors
+	Q [][]float64 // items x factors
+
+	factors  int
+	implicit bool
+	alpha    float64 // confidence scaling for implicit feedback
+
+	// itemStoreOnce guards the lazy build of itemStore: DataStore.ProcessRecommendations
+	// reaches UserRecs/ItemRecs from multiple goroutines sharing this model.
+	itemStoreOnce sync.Once
+	itemStore     *VectorStore // cached ANN index over Q, built lazily
+}
+
+// newMFModel builds the user/item index from interactions (a snapshot of
+// ds.Interactions taken by the caller) and initializes P and Q with small
+// random values.
+func newMFModel(ds *DataStore, interactions map[string]map[string]float64, factors int, implicit bool, alpha float64) *MFModel {
+	rng := rand.New(rand.NewSource(mfSeed))
+
+	userIndex := make(map[string]int)
+	itemIndex := make(map[string]int)
+	var userIDs, itemIDs []string
+	for userID, targets := range interactions {
+		if _, ok := userIndex[userID]; !ok {
+			userIndex[userID] = len(userIDs)
+			userIDs = append(userIDs, userID)
+		}
+		for targetID := range targets {
+			if _, ok := itemIndex[targetID]; !ok {
+				itemIndex[targetID] = len(itemIDs)
+				itemIDs = append(itemIDs, targetID)
+			}
+		}
+	}
+
+	return &MFModel{
+		ds:        ds,
+		userIndex: userIndex,
+		itemIndex: itemIndex,
+		userIDs:   userIDs,
+		itemIDs:   itemIDs,
+		P:         randomFactorMatrix(rng, len(userIDs), factors),
+		Q:         randomFactorMatrix(rng, len(itemIDs), factors),
+		factors:   factors,
+		implicit:  implicit,
+		alpha:     alpha,
+	}
+}
+
+func randomFactorMatrix(rng *rand.Rand, rows, factors int) [][]float64 {
+	m := make([][]float64, rows)
+	for i := range m {
+		row := make([]float64, factors)
+		for f := range row {
+			row[f] = rng.NormFloat64() * 0.1
+		}
+		m[i] = row
+	}
+	return m
+}
+
+func dot(a, b []float64) float64 {
+	sum := 0.0
+	for i := range a {
+		sum += a[i] * b[i]
+	}
+	return sum
+}
+
+// FitExplicit trains a matrix-factorization model on explicit feedback
+// scores (e.g. investment amounts, ratings) in ds.Interactions using
+// stochastic gradient descent, and stores the result as the DataStore's
+// active MF model. Training runs against a single snapshot of
+// ds.Interactions taken up front, so a concurrent AddInteraction can't
+// race the fit or change its outcome mid-training.
+func (ds *DataStore) FitExplicit(iterations, factors int, learningRate, regularization float64) *MFModel {
+	interactions := ds.allInteractions()
+	model := newMFModel(ds, interactions, factors, false, 0)
+
+	for iter := 0; iter < iterations; iter++ {
+		for userID, targets := range interactions {
+			ui, ok := model.userIndex[userID]
+			if !ok {
+				continue
+			}
+			for targetID, score := range targets {
+				ii, ok := model.itemIndex[targetID]
+				if !ok {
+					continue
+				}
+				pu, qi := model.P[ui], model.Q[ii]
+				err := score - dot(pu, qi)
+				for f := 0; f < factors; f++ {
+					newPuf := pu[f] + learningRate*(err*qi[f]-regularization*pu[f])
+					newQif := qi[f] + learningRate*(err*pu[f]-regularization*qi[f])
+					pu[f], qi[f] = newPuf, newQif
+				}
+			}
+		}
+	}
+
+	ds.mfModel = model
+	return model
+}
+
+// FitImplicit trains a matrix-factorization model on implicit feedback
+// (views, follows, likes) using alternating least squares over a binary
+// preference matrix with confidence weights c_ui = 1 + alpha*value, and
+// stores the result as the DataStore's active MF model. Training runs
+// against a single snapshot of ds.Interactions taken up front, so a
+// concurrent AddInteraction can't race the fit or change its outcome
+// mid-training.
+func (ds *DataStore) FitImplicit(iterations, factors int, regularization, alpha float64) *MFModel {
+	interactions := ds.allInteractions()
+	model := newMFModel(ds, interactions, factors, true, alpha)
+	if len(model.userIDs) == 0 || len(model.itemIDs) == 0 {
+		ds.mfModel = model
+		return model
+	}
+
+	itemTargets := make(map[string]map[string]float64)
+	for userID, targets := range interactions {
+		for targetID, value := range targets {
+			if itemTargets[targetID] == nil {
+				itemTargets[targetID] = make(map[string]float64)
+			}
+			itemTargets[targetID][userID] = value
+		}
+	}
+
+	for iter := 0; iter < iterations; iter++ {
+		alsSolve(model.P, model.Q, model.itemIndex, interactions, model.userIndex, model.alpha, regularization, model.factors)
+		alsSolve(model.Q, model.P, model.userIndex, itemTargets, model.itemIndex, model.alpha, regularization, model.factors)
+	}
+
+	ds.mfModel = model
+	return model
+}
+
+// alsSolve updates each row of `solving` in place by solving the ALS
+// normal equations against the fixed side `fixed`, exploiting a
+// precomputed fixed^T*fixed (O(k^2) per row instead of O(k^2*|items|)).
+func alsSolve(solving, fixed [][]float64, fixedIndex map[string]int, rows map[string]map[string]float64, rowIndex map[string]int, alpha, regularization float64, factors int) {
+	gram := gramMatrix(fixed, factors)
+	reg := make([][]float64, factors)
+	for i := range reg {
+		reg[i] = make([]float64, factors)
+		reg[i][i] = regularization
+	}
+
+	for rowID, targets := range rows {
+		idx, ok := rowIndex[rowID]
+		if !ok {
+			continue
+		}
+		a := addMatrix(gram, reg)
+		b := make([]float64, factors)
+		for targetID, value := range targets {
+			fi, ok := fixedIndex[targetID]
+			if !ok {
+				continue
+			}
+			y := fixed[fi]
+			c := 1 + alpha*value
+			for i := 0; i < factors; i++ {
+				for j := 0; j < factors; j++ {
+					a[i][j] += (c - 1) * y[i] * y[j]
+				}
+				b[i] += c * y[i] // preference p=1 for every observed interaction
+			}
+		}
+		solving[idx] = solveLinearSystem(a, b)
+	}
+}
+
+func gramMatrix(m [][]float64, factors int) [][]float64 {
+	g := make([][]float64, factors)
+	for i := range g {
+		g[i] = make([]float64, factors)
+	}
+	for _, row := range m {
+		for i := 0; i < factors; i++ {
+			for j := 0; j < factors; j++ {
+				g[i][j] += row[i] * row[j]
+			}
+		}
+	}
+	return g
+}
+
+func addMatrix(a, b [][]float64) [][]float64 {
+	out := make([][]float64, len(a))
+	for i := range a {
+		out[i] = make([]float64, len(a[i]))
+		for j := range a[i] {
+			out[i][j] = a[i][j] + b[i][j]
+		}
+	}
+	return out
+}
+
+// solveLinearSystem solves Ax = b via Gaussian elimination with partial
+// pivoting. A and b are left untouched; the solution is returned as a new
+// slice. Singular dimensions fall back to 0 rather than propagating NaN.
+func solveLinearSystem(a [][]float64, b []float64) []float64 {
+	n := len(b)
+	m := make([][]float64, n)
+	for i := range a {
+		m[i] = append([]float64(nil), a[i]...)
+	}
+	x := append([]float64(nil), b...)
+
+	for col := 0; col < n; col++ {
+		pivot := col
+		for row := col + 1; row < n; row++ {
+			if math.Abs(m[row][col]) > math.Abs(m[pivot][col]) {
+				pivot = row
+			}
+		}
+		m[col], m[pivot] = m[pivot], m[col]
+		x[col], x[pivot] = x[pivot], x[col]
+
+		if m[col][col] == 0 {
+			continue
+		}
+		for row := col + 1; row < n; row++ {
+			factor := m[row][col] / m[col][col]
+			for k := col; k < n; k++ {
+				m[row][k] -= factor * m[col][k]
+			}
+			x[row] -= factor * x[col]
+		}
+	}
+
+	result := make([]float64, n)
+	for i := n - 1; i >= 0; i-- {
+		sum := x[i]
+		for j := i + 1; j < n; j++ {
+			sum -= m[i][j] * result[j]
+		}
+		if m[i][i] == 0 {
+			continue
+		}
+		result[i] = sum / m[i][i]
+	}
+	return result
+}
+
+// Predict returns the model's estimated affinity between a user and item.
+// Cold-start users or items not seen during training yield a score of 0.
+func (m *MFModel) Predict(userID, itemID string) float64 {
+	ui, uok := m.userIndex[userID]
+	ii, iok := m.itemIndex[itemID]
+	if !uok || !iok {
+		return 0
+	}
+	return dot(m.P[ui], m.Q[ii])
+}
+
+// UserRecs returns the top-n items predicted for userID via a single ANN
+// query against the model's item embeddings, excluding items the user has
+// already interacted with. Returns nil for cold-start users.
+func (m *MFModel) UserRecs(userID string, n int) []Recommendation {
+	ui, ok := m.userIndex[userID]
+	if !ok {
+		return nil
+	}
+	seen := m.ds.interactionsSnapshot(userID)
+
+	recs := m.itemVectorStore().FindTopK(float32Vec(m.P[ui]), n, func(itemID string) bool {
+		_, exists := seen[itemID]
+		return !exists
+	})
+	for i := range recs {
+		recs[i].Type = m.targetType(recs[i].TargetID)
+	}
+	return recs
+}
+
+// ItemRecs returns the top-n items whose latent factors are most similar
+// to itemID's, useful for "more like this" surfaces.
+func (m *MFModel) ItemRecs(itemID string, n int) []Recommendation {
+	ii, ok := m.itemIndex[itemID]
+	if !ok {
+		return nil
+	}
+
+	recs := m.itemVectorStore().FindTopK(float32Vec(m.Q[ii]), n, func(otherID string) bool {
+		return otherID != itemID
+	})
+	for i := range recs {
+		recs[i].Type = m.targetType(recs[i].TargetID)
+	}
+	return recs
+}
+
+// itemVectorStore lazily builds (and caches) the VectorStore of item
+// embeddings backing UserRecs and ItemRecs, guarded by itemStoreOnce since
+// ProcessRecommendations reaches this from multiple goroutines. It always
+// scores by raw dot product (NewDotProductVectorStore): P/Q are never
+// normalized, and SGD/ALS optimized them against a raw dot product, the
+// same one Predict() reports — cosine would silently rank by a different
+// (and possibly inverted) order.
+func (m *MFModel) itemVectorStore() *VectorStore {
+	m.itemStoreOnce.Do(func() {
+		store := NewDotProductVectorStore()
+		for itemID, ii := range m.itemIndex {
+			store.Upsert(itemID, float32Vec(m.Q[ii]))
+		}
+		m.itemStore = store
+	})
+	return m.itemStore
+}
+
+func (m *MFModel) targetType(id string) string {
+	if _, exists := m.ds.Users[id]; exists {
+		return "user"
+	}
+	return "startup"
+}
+
+// MFRecommendations returns matrix-factorization recommendations for
+// userID using the DataStore's most recently fitted MF model, falling
+// back to popular items when there is no model or the user is unseen
+// (cold start).
+func (ds *DataStore) MFRecommendations(userID string, n int) []Recommendation {
+	if ds.mfModel != nil {
+		if recs := ds.mfModel.UserRecs(userID, n); recs != nil {
+			return recs
+		}
+	}
+
+	var recs []Recommendation
+	for _, itemID := range ds.GetPopularItems(n) {
+		itemType := "startup"
+		if _, exists := ds.Users[itemID]; exists {
+			itemType = "user"
+		}
+		recs = append(recs, Recommendation{TargetID: itemID, Type: itemType, Score: 0.5})
+	}
+	return recs
+}
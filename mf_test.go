@@ -0,0 +1,86 @@
+package main
+
+import "testing"
+
+func TestSolveLinearSystem(t *testing.T) {
+	// 2a + b = 3
+	// a + 3b = 5
+	a := [][]float64{{2, 1}, {1, 3}}
+	b := []float64{3, 5}
+
+	got := solveLinearSystem(a, b)
+	want := []float64{0.8, 1.4}
+
+	for i := range want {
+		if diff := got[i] - want[i]; diff > 1e-9 || diff < -1e-9 {
+			t.Errorf("solveLinearSystem(%v, %v)[%d] = %v, want %v", a, b, i, got[i], want[i])
+		}
+	}
+}
+
+func TestFitExplicitRecoversKnownRatings(t *testing.T) {
+	ds := NewDataStore()
+	ds.Interactions = map[string]map[string]float64{
+		"u1": {"s1": 5.0, "s2": 1.0},
+		"u2": {"s1": 1.0, "s2": 5.0},
+	}
+	ds.FeedbackTypes = map[string]map[string]FeedbackType{
+		"u1": {"s1": ExplicitFeedback, "s2": ExplicitFeedback},
+		"u2": {"s1": ExplicitFeedback, "s2": ExplicitFeedback},
+	}
+
+	model := ds.FitExplicit(500, 4, 0.05, 0.02)
+
+	cases := []struct {
+		userID, itemID string
+		want           float64
+	}{
+		{"u1", "s1", 5.0},
+		{"u1", "s2", 1.0},
+		{"u2", "s1", 1.0},
+		{"u2", "s2", 5.0},
+	}
+	for _, c := range cases {
+		got := model.Predict(c.userID, c.itemID)
+		if diff := got - c.want; diff > 0.5 || diff < -0.5 {
+			t.Errorf("Predict(%q, %q) = %v, want ~%v", c.userID, c.itemID, got, c.want)
+		}
+	}
+}
+
+func TestFitImplicitRanksObservedAboveUnobserved(t *testing.T) {
+	ds := NewDataStore()
+	ds.Interactions = map[string]map[string]float64{
+		"u1": {"s1": 3.0},
+		"u2": {"s2": 3.0},
+	}
+	ds.FeedbackTypes = map[string]map[string]FeedbackType{
+		"u1": {"s1": ImplicitFeedback},
+		"u2": {"s2": ImplicitFeedback},
+	}
+
+	model := ds.FitImplicit(50, 4, 0.1, 1.0)
+
+	if got, other := model.Predict("u1", "s1"), model.Predict("u1", "s2"); got <= other {
+		t.Errorf("Predict(u1, s1) = %v, want it to rank above Predict(u1, s2) = %v", got, other)
+	}
+}
+
+func TestUserRecsExcludesSeenItems(t *testing.T) {
+	ds := NewDataStore()
+	ds.Interactions = map[string]map[string]float64{
+		"u1": {"s1": 5.0},
+		"u2": {"s2": 5.0},
+	}
+	ds.FeedbackTypes = map[string]map[string]FeedbackType{
+		"u1": {"s1": ExplicitFeedback},
+		"u2": {"s2": ExplicitFeedback},
+	}
+
+	model := ds.FitExplicit(50, 4, 0.05, 0.02)
+	for _, rec := range model.UserRecs("u1", 5) {
+		if rec.TargetID == "s1" {
+			t.Errorf("UserRecs(u1) returned already-seen item s1: %+v", rec)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"math"
+	"sort"
+)
+
+// trustOf returns userID's trust score, defaulting to full trust (1.0)
+// when unset so users without an explicit Trust behave exactly as they
+// did before Trust was introduced. TrustSet distinguishes "never set"
+// from an explicit, meaningful Trust of 0 (zero credibility).
+func (ds *DataStore) trustOf(userID string) float64 {
+	if user, exists := ds.Users[userID]; exists && user.TrustSet {
+		return user.Trust
+	}
+	return 1.0
+}
+
+// ComputeTrust derives each user's Trust score from the graph instead of
+// relying on it being supplied: it runs PageRank-style propagation
+// (damping d=0.85) over the user-user "followed/funded" interaction
+// edges, iterating until the maximum score delta drops below 1e-6.
+// Dangling nodes (users with no outgoing followed/funded edges) simply
+// stop propagating rank rather than redistributing it, which is fine at
+// this graph's scale.
+//
+// Raw PageRank mass sums to ~1 across all users, so it shrinks toward 0
+// as the user graph grows regardless of how well-connected any one user
+// is — directly comparable to the 1.0 default trustOf uses for a user
+// ComputeTrust has never touched. Before writing back to ds.Users, the
+// scores are rescaled by the graph's max score, so the best-connected
+// user lands at 1.0 and every other user's Trust is relative to them.
+func (ds *DataStore) ComputeTrust() {
+	const damping = 0.85
+	const epsilon = 1e-6
+
+	userIDs := make([]string, 0, len(ds.Users))
+	for id := range ds.Users {
+		userIDs = append(userIDs, id)
+	}
+	sort.Strings(userIDs) // deterministic iteration order
+
+	outDegree := make(map[string]int)
+	inLinks := make(map[string][]string) // userID -> users with an edge into it
+	for userID, targets := range ds.allInteractions() {
+		if _, exists := ds.Users[userID]; !exists {
+			continue
+		}
+		for targetID := range targets {
+			if _, exists := ds.Users[targetID]; !exists {
+				continue // trust only propagates over user-user edges
+			}
+			outDegree[userID]++
+			inLinks[targetID] = append(inLinks[targetID], userID)
+		}
+	}
+
+	n := float64(len(userIDs))
+	if n == 0 {
+		return
+	}
+	scores := make(map[string]float64, len(userIDs))
+	for _, id := range userIDs {
+		scores[id] = 1.0 / n
+	}
+
+	for {
+		next := make(map[string]float64, len(userIDs))
+		maxDelta := 0.0
+		for _, id := range userIDs {
+			rank := (1 - damping) / n
+			for _, src := range inLinks[id] {
+				rank += damping * scores[src] / float64(outDegree[src])
+			}
+			next[id] = rank
+			if delta := math.Abs(rank - scores[id]); delta > maxDelta {
+				maxDelta = delta
+			}
+		}
+		scores = next
+		if maxDelta < epsilon {
+			break
+		}
+	}
+
+	maxScore := 0.0
+	for _, score := range scores {
+		if score > maxScore {
+			maxScore = score
+		}
+	}
+
+	for _, id := range userIDs {
+		user := ds.Users[id]
+		if maxScore > 0 {
+			user.Trust = scores[id] / maxScore
+		} else {
+			user.Trust = 0
+		}
+		user.TrustSet = true
+		ds.Users[id] = user
+	}
+}
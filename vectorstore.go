@@ -0,0 +1,175 @@
+package main
+
+import (
+	"container/heap"
+	"math"
+	"sort"
+)
+
+// VectorStore holds dense embedding vectors keyed by item/user ID and
+// answers top-k similarity queries without a full scan-and-sort, so
+// recommenders can scale past a linear pass over ds.Users/ds.Features.
+type VectorStore struct {
+	vectors map[string][]float32
+
+	// keysAreNormalized is true as long as every vector ever Upserted has
+	// ||v||=1, letting FindTopK score by pure dot product and skip norm
+	// computation entirely.
+	keysAreNormalized bool
+
+	// forceDotProduct opts out of the cosine fallback entirely. Use this
+	// for embeddings (like MF's P/Q factors) whose magnitude is itself
+	// signal and that were optimized against a raw dot product: cosine
+	// would discard that magnitude and can invert their true top-k order.
+	forceDotProduct bool
+}
+
+// NewVectorStore returns an empty VectorStore that scores by cosine
+// similarity unless every vector it ever holds turns out to be
+// unit-normalized, in which case it scores by dot product.
+func NewVectorStore() *VectorStore {
+	return &VectorStore{
+		vectors:           make(map[string][]float32),
+		keysAreNormalized: true, // vacuously true until a non-unit vector is upserted
+	}
+}
+
+// NewDotProductVectorStore returns an empty VectorStore that always scores
+// by raw dot product, regardless of whether stored vectors are
+// normalized. Use this for embeddings whose magnitude is meaningful.
+func NewDotProductVectorStore() *VectorStore {
+	return &VectorStore{
+		vectors:         make(map[string][]float32),
+		forceDotProduct: true,
+	}
+}
+
+// Upsert inserts or replaces the embedding stored under id.
+func (vs *VectorStore) Upsert(id string, vec []float32) {
+	vs.vectors[id] = vec
+	if vs.keysAreNormalized && !isUnitVector(vec) {
+		vs.keysAreNormalized = false
+	}
+}
+
+// DeleteKey removes id's embedding, if present.
+func (vs *VectorStore) DeleteKey(id string) {
+	delete(vs.vectors, id)
+}
+
+func isUnitVector(vec []float32) bool {
+	sum := 0.0
+	for _, v := range vec {
+		sum += float64(v) * float64(v)
+	}
+	return math.Abs(sum-1) < 1e-6
+}
+
+// scoredKey pairs a stored key with its score against the current query,
+// so a single heap type can serve as the top-k min-heap in FindTopK.
+type scoredKey struct {
+	id    string
+	score float64
+}
+
+type topKHeap []scoredKey
+
+func (h topKHeap) Len() int           { return len(h) }
+func (h topKHeap) Less(i, j int) bool { return h[i].score < h[j].score } // min at root
+func (h topKHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+
+func (h *topKHeap) Push(x any) { *h = append(*h, x.(scoredKey)) }
+
+func (h *topKHeap) Pop() any {
+	old := *h
+	n := len(old)
+	item := old[n-1]
+	*h = old[:n-1]
+	return item
+}
+
+// FindTopK returns the k highest-scoring vectors against query, restricted
+// to keys for which filter returns true (a nil filter matches everything).
+// Scoring is a pure dot product when forceDotProduct or keysAreNormalized
+// is set (skipping norm computation entirely), or cosine similarity
+// otherwise. Results are tracked in a size-k min-heap: a candidate is only
+// pushed once it beats the current worst of the top-k, avoiding a full
+// sort of every vector in the store.
+func (vs *VectorStore) FindTopK(query []float32, k int, filter func(id string) bool) []Recommendation {
+	if k <= 0 {
+		return nil
+	}
+
+	queryNorm := 0.0
+	if !vs.forceDotProduct && !vs.keysAreNormalized {
+		for _, v := range query {
+			queryNorm += float64(v) * float64(v)
+		}
+		queryNorm = math.Sqrt(queryNorm)
+	}
+
+	h := &topKHeap{}
+	for id, vec := range vs.vectors {
+		if filter != nil && !filter(id) {
+			continue
+		}
+		score := vs.score(query, vec, queryNorm)
+		if h.Len() < k {
+			heap.Push(h, scoredKey{id: id, score: score})
+		} else if score > (*h)[0].score {
+			heap.Pop(h)
+			heap.Push(h, scoredKey{id: id, score: score})
+		}
+	}
+
+	items := make([]scoredKey, h.Len())
+	copy(items, *h)
+	sort.Slice(items, func(i, j int) bool { return items[i].score > items[j].score })
+
+	recs := make([]Recommendation, len(items))
+	for i, it := range items {
+		recs[i] = Recommendation{TargetID: it.id, Score: it.score}
+	}
+	return recs
+}
+
+func (vs *VectorStore) score(query, vec []float32, queryNorm float64) float64 {
+	dotP := 0.0
+	for i := 0; i < len(query) && i < len(vec); i++ {
+		dotP += float64(query[i]) * float64(vec[i])
+	}
+	if vs.forceDotProduct || vs.keysAreNormalized {
+		return dotP
+	}
+	vecNorm := 0.0
+	for _, v := range vec {
+		vecNorm += float64(v) * float64(v)
+	}
+	vecNorm = math.Sqrt(vecNorm)
+	if queryNorm == 0 || vecNorm == 0 {
+		return 0
+	}
+	return dotP / (queryNorm * vecNorm)
+}
+
+// denseVector projects a sparse feature map onto the dense coordinate
+// space described by dims (feature name -> index).
+func denseVector(sparse map[string]float64, dims map[string]int) []float32 {
+	vec := make([]float32, len(dims))
+	for feature, value := range sparse {
+		if idx, ok := dims[feature]; ok {
+			vec[idx] = float32(value)
+		}
+	}
+	return vec
+}
+
+// float32Vec converts a []float64 factor row into the []float32 format
+// VectorStore expects.
+func float32Vec(v []float64) []float32 {
+	out := make([]float32, len(v))
+	for i, x := range v {
+		out[i] = float32(x)
+	}
+	return out
+}
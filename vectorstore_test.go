@@ -0,0 +1,131 @@
+package main
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+// bruteForceTopK scores every vector with the same cosine/dot logic
+// VectorStore uses and sorts, as an oracle to check FindTopK's heap
+// against.
+func bruteForceTopK(vectors map[string][]float32, forceDotProduct bool, query []float32, k int, filter func(string) bool) []Recommendation {
+	type scored struct {
+		id    string
+		score float64
+	}
+	var all []scored
+	for id, vec := range vectors {
+		if filter != nil && !filter(id) {
+			continue
+		}
+		all = append(all, scored{id: id, score: bruteScore(query, vec, forceDotProduct)})
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].score > all[j].score })
+	if len(all) > k {
+		all = all[:k]
+	}
+	recs := make([]Recommendation, len(all))
+	for i, s := range all {
+		recs[i] = Recommendation{TargetID: s.id, Score: s.score}
+	}
+	return recs
+}
+
+func bruteScore(query, vec []float32, forceDotProduct bool) float64 {
+	dotP := 0.0
+	for i := 0; i < len(query) && i < len(vec); i++ {
+		dotP += float64(query[i]) * float64(vec[i])
+	}
+	if forceDotProduct {
+		return dotP
+	}
+	qNorm, vNorm := 0.0, 0.0
+	for _, v := range query {
+		qNorm += float64(v) * float64(v)
+	}
+	for _, v := range vec {
+		vNorm += float64(v) * float64(v)
+	}
+	qNorm, vNorm = math.Sqrt(qNorm), math.Sqrt(vNorm)
+	if qNorm == 0 || vNorm == 0 {
+		return 0
+	}
+	return dotP / (qNorm * vNorm)
+}
+
+func TestFindTopKMatchesBruteForceCosine(t *testing.T) {
+	rng := rand.New(rand.NewSource(7))
+	store := NewVectorStore()
+	vectors := make(map[string][]float32)
+	for i := 0; i < 50; i++ {
+		id := string(rune('a' + i%26))
+		vec := []float32{rng.Float32()*2 - 1, rng.Float32()*2 - 1, rng.Float32()*2 - 1}
+		store.Upsert(id, vec)
+		vectors[id] = vec
+	}
+	query := []float32{0.3, -0.6, 0.1}
+
+	got := store.FindTopK(query, 5, nil)
+	want := bruteForceTopK(vectors, false, query, 5, nil)
+
+	if len(got) != len(want) {
+		t.Fatalf("FindTopK returned %d results, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i].TargetID != want[i].TargetID {
+			t.Errorf("result[%d] = %q, want %q (got scores %+v, want %+v)", i, got[i].TargetID, want[i].TargetID, got, want)
+		}
+	}
+}
+
+func TestFindTopKMatchesBruteForceDotProduct(t *testing.T) {
+	store := NewDotProductVectorStore()
+	vectors := map[string][]float32{
+		"small": {0.5, 0},
+		"big":   {10, 9},
+		"mid":   {2, 1},
+	}
+	for id, vec := range vectors {
+		store.Upsert(id, vec)
+	}
+	query := []float32{1, 0}
+
+	got := store.FindTopK(query, 3, nil)
+	want := bruteForceTopK(vectors, true, query, 3, nil)
+
+	for i := range want {
+		if got[i].TargetID != want[i].TargetID {
+			t.Errorf("result[%d] = %q, want %q (got %+v, want %+v)", i, got[i].TargetID, want[i].TargetID, got, want)
+		}
+	}
+	if got[0].TargetID != "big" {
+		t.Errorf("expected dot-product scoring to rank %q first, got %+v", "big", got)
+	}
+}
+
+func TestFindTopKRespectsFilter(t *testing.T) {
+	store := NewVectorStore()
+	store.Upsert("a", []float32{1, 0})
+	store.Upsert("b", []float32{0, 1})
+	store.Upsert("c", []float32{1, 1})
+
+	recs := store.FindTopK([]float32{1, 0}, 3, func(id string) bool { return id != "a" })
+	for _, r := range recs {
+		if r.TargetID == "a" {
+			t.Errorf("FindTopK returned filtered-out key %q: %+v", "a", recs)
+		}
+	}
+	if len(recs) != 2 {
+		t.Errorf("expected 2 results after filtering out one of three keys, got %d", len(recs))
+	}
+}
+
+func TestFindTopKZeroK(t *testing.T) {
+	store := NewVectorStore()
+	store.Upsert("a", []float32{1, 0})
+	if recs := store.FindTopK([]float32{1, 0}, 0, nil); recs != nil {
+		t.Errorf("FindTopK with k=0 = %+v, want nil", recs)
+	}
+}